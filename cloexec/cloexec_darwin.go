@@ -0,0 +1,98 @@
+// Copyright 2026 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+//go:build darwin
+
+package cloexec
+
+/*
+#include <unistd.h>
+#include <fcntl.h>
+#include <libproc.h>
+
+// fcntl is variadic in C and cgo cannot call variadic functions
+// directly, so funnel the int-arg forms godror needs through this.
+static int godror_fcntl_int(int fd, int cmd, int arg) {
+	return fcntl(fd, cmd, arg);
+}
+*/
+import "C"
+import (
+	"fmt"
+	"os"
+	"unsafe"
+)
+
+func setFd(fd uintptr, set bool) error {
+	flags, err := C.godror_fcntl_int(C.int(fd), C.F_GETFD, 0)
+	if flags < 0 {
+		return fmt.Errorf("fcntl F_GETFD %d: %w", fd, err)
+	}
+	if set {
+		flags |= C.FD_CLOEXEC
+	} else {
+		flags &^= C.FD_CLOEXEC
+	}
+	if rc, err := C.godror_fcntl_int(C.int(fd), C.F_SETFD, flags); rc < 0 {
+		return fmt.Errorf("fcntl F_SETFD %d: %w", fd, err)
+	}
+	return nil
+}
+
+func getFd(fd uintptr) (bool, error) {
+	flags, err := C.godror_fcntl_int(C.int(fd), C.F_GETFD, 0)
+	if flags < 0 {
+		return false, fmt.Errorf("fcntl F_GETFD %d: %w", fd, err)
+	}
+	return flags&C.FD_CLOEXEC != 0, nil
+}
+
+// getConnections enumerates the current process's open socket file
+// descriptors using libproc's proc_pidinfo(PROC_PIDLISTFDS) +
+// proc_pidfdinfo(PROC_PIDFDSOCKETINFO), since macOS has no /proc.
+func getConnections(kind string) ([]uint32, error) {
+	if kind == "" {
+		kind = "tcp"
+	}
+	pid := C.int(os.Getpid())
+
+	size, err := C.proc_pidinfo(pid, C.PROC_PIDLISTFDS, 0, nil, 0)
+	if size <= 0 {
+		return nil, fmt.Errorf("proc_pidinfo PROC_PIDLISTFDS: %w", err)
+	}
+	n := int(size) / int(unsafe.Sizeof(C.struct_proc_fdinfo{}))
+	buf := make([]C.struct_proc_fdinfo, n)
+	size, err = C.proc_pidinfo(pid, C.PROC_PIDLISTFDS, 0, unsafe.Pointer(&buf[0]), size)
+	if size <= 0 {
+		return nil, fmt.Errorf("proc_pidinfo PROC_PIDLISTFDS: %w", err)
+	}
+
+	var fds []uint32
+	for _, info := range buf[:int(size)/int(unsafe.Sizeof(C.struct_proc_fdinfo{}))] {
+		if info.proc_fdtype != C.PROX_FDTYPE_SOCKET {
+			continue
+		}
+		var sockInfo C.struct_socket_fdinfo
+		if C.proc_pidfdinfo(pid, info.proc_fd, C.PROC_PIDFDSOCKETINFO, unsafe.Pointer(&sockInfo), C.int(unsafe.Sizeof(sockInfo))) <= 0 {
+			continue
+		}
+		if kind != "all" {
+			switch sockInfo.psi.soi_kind {
+			case C.SOCKINFO_TCP:
+				if kind != "tcp" {
+					continue
+				}
+			case C.SOCKINFO_IN:
+				if kind != "udp" {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		fds = append(fds, uint32(info.proc_fd))
+	}
+	return fds, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+//go:build windows
+
+package cloexec
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+func setFd(fd uintptr, set bool) error {
+	var flag uint32
+	if set {
+		// HANDLE_FLAG_INHERIT unset means close-on-exec, by Windows
+		// convention, so "set" clears the inherit flag.
+		flag = 0
+	} else {
+		flag = windows.HANDLE_FLAG_INHERIT
+	}
+	if err := windows.SetHandleInformation(windows.Handle(fd), windows.HANDLE_FLAG_INHERIT, flag); err != nil {
+		return fmt.Errorf("SetHandleInformation(%d): %w", fd, err)
+	}
+	return nil
+}
+
+func getFd(fd uintptr) (bool, error) {
+	var flags uint32
+	if err := windows.GetHandleInformation(windows.Handle(fd), &flags); err != nil {
+		return false, fmt.Errorf("GetHandleInformation(%d): %w", fd, err)
+	}
+	return flags&windows.HANDLE_FLAG_INHERIT == 0, nil
+}
+
+// getConnections has no portable way to enumerate a process's open
+// sockets on Windows short of walking the handle table with
+// NtQuerySystemInformation, which godror does not link against. It
+// always returns ErrUnsupported, so SetNetConnections, Snapshot and
+// SetNewConnections (and with them Drv.SetCloseOnExec) flag nothing on
+// Windows; only SetFd and ClearFd on a known handle work here.
+func getConnections(kind string) ([]uint32, error) {
+	return nil, fmt.Errorf("cloexec: enumerate %q connections: %w", kind, ErrUnsupported)
+}
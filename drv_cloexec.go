@@ -0,0 +1,72 @@
+// Copyright 2026 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"database/sql/driver"
+	"sync"
+
+	"github.com/godror/godror/cloexec"
+)
+
+// Drv is the database/sql/driver.Driver godror registers itself as.
+type Drv struct {
+	mu          sync.Mutex
+	closeOnExec bool
+}
+
+// SetCloseOnExec, when enabled, makes every new OCI-owned socket close
+// automatically on exec as soon as the connection that opened it is
+// established, instead of requiring a retroactive SetNetConnections
+// sweep. This matters for servers that pool connections and later spawn
+// subprocesses (os/exec, archive/... shellouts, etc.) - without it, such
+// a subprocess inherits every open DB socket.
+//
+// Existing connections are unaffected; call cloexec.SetNetConnections
+// once after turning this on to catch sockets opened earlier.
+//
+// On Windows the cloexec package cannot enumerate sockets, so this flags
+// no socket there; the resulting error only reaches Log, if set.
+func (d *Drv) SetCloseOnExec(b bool) {
+	d.mu.Lock()
+	d.closeOnExec = b
+	d.mu.Unlock()
+}
+
+// Open implements driver.Driver, opening a new OCI connection for dsn
+// and, when SetCloseOnExec is enabled, tagging only the socket(s) that
+// connection just opened - via a before/after Snapshot diff, not a
+// process-wide SetNetConnections sweep - so it never marks sockets
+// unrelated connections already own. Both snapshots still enumerate
+// every socket of the process.
+func (d *Drv) Open(dsn string) (driver.Conn, error) {
+	d.mu.Lock()
+	closeOnExec := d.closeOnExec
+	d.mu.Unlock()
+
+	var before []uint32
+	if closeOnExec {
+		before, _ = cloexec.Snapshot("tcp")
+	}
+
+	c, err := d.openConn(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if closeOnExec {
+		d.markCloseOnExec(before)
+	}
+	return c, nil
+}
+
+// markCloseOnExec tags the socket(s) opened since before was captured,
+// right after OCI hands back a new connection, so they don't leak into
+// subprocesses spawned later.
+func (d *Drv) markCloseOnExec(before []uint32) {
+	if err := cloexec.SetNewConnections("tcp", before); err != nil && Log != nil {
+		Log("msg", "markCloseOnExec", "error", err)
+	}
+}
@@ -0,0 +1,502 @@
+// Copyright 2019, 2020, 2026 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+/*
+#include <stdlib.h>
+#include "dpiImpl.h"
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// Execer is the interface needed to create a Queue: anything that can
+// return a raw, underlying godror connection. *sql.Conn satisfies this.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Queue represents an Oracle Advanced Queue (DBMS_AQ / DBMS_AQADM).
+//
+// See https://www.godror.org/docs/Queue.html
+type Queue struct {
+	conn              *conn
+	name              string
+	payloadObjectType *ObjectType
+	isJSON            bool
+	dpiQueue          *C.dpiQueue
+	maxInlineBytes    int64
+
+	mu sync.Mutex
+}
+
+// QueueOption configures a Queue created with NewQueue.
+type QueueOption func(*Queue)
+
+// NewQueue creates a new Queue bound to the given connection.
+//
+// payloadObjectType is the name of the object type of the payload, or ""
+// for RAW payloads. The connection must be kept open for as long as the
+// Queue (and any Subscription on it) is in use.
+func NewQueue(ctx context.Context, execer Execer, name, payloadObjectType string, options ...QueueOption) (*Queue, error) {
+	c, err := getConn(ctx, execer)
+	if err != nil {
+		return nil, fmt.Errorf("newQueue %q: %w", name, err)
+	}
+	Q := &Queue{conn: c, name: name}
+	for _, o := range options {
+		o(Q)
+	}
+	if payloadObjectType != "" {
+		if Q.isJSON {
+			return nil, fmt.Errorf("newQueue %q: payloadObjectType and WithJSONPayload are mutually exclusive", name)
+		}
+		ot, err := c.GetObjectType(ctx, payloadObjectType)
+		if err != nil {
+			return nil, fmt.Errorf("newQueue %q: %w", name, err)
+		}
+		Q.payloadObjectType = ot
+	}
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	var objType *C.dpiObjectType
+	if Q.payloadObjectType != nil {
+		objType = Q.payloadObjectType.dpiObjectType
+	}
+	if C.dpiConn_newQueue(c.dpiConn, cName, C.uint32_t(len(name)), objType, &Q.dpiQueue) == C.DPI_FAILURE {
+		return nil, fmt.Errorf("newQueue %q: %w", name, c.getError())
+	}
+	return Q, nil
+}
+
+// Name returns the name of the queue.
+func (Q *Queue) Name() string { return Q.name }
+
+// Close closes the Queue, releasing the underlying dpiQueue handle.
+func (Q *Queue) Close() error {
+	Q.mu.Lock()
+	defer Q.mu.Unlock()
+	if Q.dpiQueue == nil {
+		return nil
+	}
+	q := Q.dpiQueue
+	Q.dpiQueue = nil
+	if C.dpiQueue_release(q) == C.DPI_FAILURE {
+		return fmt.Errorf("release queue %q: %w", Q.name, Q.conn.getError())
+	}
+	return nil
+}
+
+// Message is a single Advanced Queuing message, either enqueued or
+// received from a Dequeue call.
+type Message struct {
+	// Raw is the RAW payload of the message. Mutually exclusive with
+	// Object and JSON.
+	Raw []byte
+	// Object is the object payload of the message. Mutually exclusive
+	// with Raw and JSON.
+	Object *Object
+	// JSON is the payload of a message enqueued or dequeued on a Queue
+	// created with WithJSONPayload (Oracle 21c+ JSON queues). Mutually
+	// exclusive with Raw and Object. Populate it with json.Marshal, or
+	// use Queue.EnqueueValue/DequeueValue to marshal/unmarshal a Go
+	// value directly.
+	JSON json.RawMessage
+
+	// Correlation is an application-defined value used to correlate
+	// enqueued and dequeued messages, and to filter dequeue with
+	// DeqOptions.Correlation.
+	Correlation string
+	// MsgID is the unique identifier of the message, filled in after
+	// enqueue, and usable as a DeqOptions.MsgID filter.
+	MsgID []byte
+	// ExceptionQ is the queue to which the message is moved if it cannot
+	// be processed successfully, e.g. once it exceeds its retry count.
+	ExceptionQ string
+	// Recipients restricts delivery of a multi-consumer queue message to
+	// the named subscribers (AQ$_RECIPIENT_LIST_T style), leave empty to
+	// deliver to all subscribers.
+	Recipients []string
+
+	Delay, Expiration time.Duration
+	Priority          int32
+}
+
+// EnqOptions holds the enqueue options of a Queue.
+type EnqOptions struct {
+	Visibility   DeqVisibility
+	DeliveryMode DeqMode
+	// MaxInlineBytes overrides DefaultMaxInlineBytes for EnqueueStream on
+	// this queue; 0 means DefaultMaxInlineBytes.
+	MaxInlineBytes int64
+}
+
+// DeqOptions holds the dequeue options of a Queue.
+type DeqOptions struct {
+	// Condition is a boolean SQL expression similar to the WHERE clause
+	// of a SQL query, used to filter the messages that may be dequeued.
+	Condition string
+	// Correlation restricts dequeue to messages enqueued with the same
+	// Message.Correlation.
+	Correlation string
+	// MsgID restricts dequeue to the message with this exact id.
+	MsgID []byte
+
+	Mode       DeqMode
+	Navigation DeqNavigation
+	Visibility DeqVisibility
+	Wait       time.Duration
+	// MaxInlineBytes, if nonzero, sets the same per-queue threshold as
+	// EnqOptions.MaxInlineBytes; 0 leaves the current threshold as it is.
+	// It only affects how EnqueueStream decides to switch to a temporary
+	// LOB; OpenReader does not use it, and it has no effect on dequeuing
+	// a message enqueued by a differently-configured producer.
+	MaxInlineBytes int64
+}
+
+// DeqMode is the dequeue mode of a Queue, mirroring dpiDeqMode.
+type DeqMode uint32
+
+// DeqNavigation is the dequeue navigation of a Queue, mirroring dpiDeqNavigation.
+type DeqNavigation uint32
+
+// DeqNavigation values, mirroring dpiDeqNavigation's.
+const (
+	// NavFirstMsg resets navigation to the first message in the queue,
+	// rather than the one following the last one dequeued.
+	NavFirstMsg DeqNavigation = iota + 1
+	// NavNextTransaction skips over any remaining messages in the current
+	// transaction group and returns the first message of the next one.
+	NavNextTransaction
+	// NavNextMsg dequeues the next message in the queue, preserving
+	// enqueue order; this is ODPI-C's own default.
+	NavNextMsg
+)
+
+// DeqVisibility is the (en/de)queue visibility of a Queue, mirroring dpiVisibility.
+type DeqVisibility uint32
+
+// EnqOptions returns the queue's current enqueue options.
+func (Q *Queue) EnqOptions() (EnqOptions, error) {
+	var opts C.dpiEnqOptions
+	if C.dpiQueue_getEnqOptions(Q.dpiQueue, &opts) == C.DPI_FAILURE {
+		return EnqOptions{}, fmt.Errorf("getEnqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	var visibility C.dpiVisibility
+	if C.dpiEnqOptions_getVisibility(&opts, &visibility) == C.DPI_FAILURE {
+		return EnqOptions{}, fmt.Errorf("getEnqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	var mode C.uint16_t
+	if C.dpiEnqOptions_getDeliveryMode(&opts, &mode) == C.DPI_FAILURE {
+		return EnqOptions{}, fmt.Errorf("getEnqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	return EnqOptions{
+		Visibility:     DeqVisibility(visibility),
+		DeliveryMode:   DeqMode(mode),
+		MaxInlineBytes: Q.maxInlineBytes,
+	}, nil
+}
+
+// SetEnqOptions applies the given enqueue options to the queue, affecting
+// every subsequent Enqueue call.
+func (Q *Queue) SetEnqOptions(opts EnqOptions) error {
+	var cOpts C.dpiEnqOptions
+	if C.dpiQueue_getEnqOptions(Q.dpiQueue, &cOpts) == C.DPI_FAILURE {
+		return fmt.Errorf("setEnqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	C.dpiEnqOptions_setVisibility(&cOpts, C.dpiVisibility(opts.Visibility))
+	C.dpiEnqOptions_setDeliveryMode(&cOpts, C.uint16_t(opts.DeliveryMode))
+	Q.maxInlineBytes = opts.MaxInlineBytes
+	return nil
+}
+
+// DeqOptions returns the queue's current dequeue options.
+func (Q *Queue) DeqOptions() (DeqOptions, error) {
+	var opts C.dpiDeqOptions
+	if C.dpiQueue_getDeqOptions(Q.dpiQueue, &opts) == C.DPI_FAILURE {
+		return DeqOptions{}, fmt.Errorf("getDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+
+	var cCond, cCorr, cMsgID *C.char
+	var condLen, corrLen, msgIDLen C.uint32_t
+	if C.dpiDeqOptions_getCondition(&opts, &cCond, &condLen) == C.DPI_FAILURE {
+		return DeqOptions{}, fmt.Errorf("getDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	if C.dpiDeqOptions_getCorrelation(&opts, &cCorr, &corrLen) == C.DPI_FAILURE {
+		return DeqOptions{}, fmt.Errorf("getDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	if C.dpiDeqOptions_getMsgId(&opts, &cMsgID, &msgIDLen) == C.DPI_FAILURE {
+		return DeqOptions{}, fmt.Errorf("getDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	var mode C.uint32_t
+	if C.dpiDeqOptions_getMode(&opts, &mode) == C.DPI_FAILURE {
+		return DeqOptions{}, fmt.Errorf("getDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	var nav C.dpiDeqNavigation
+	if C.dpiDeqOptions_getNavigation(&opts, &nav) == C.DPI_FAILURE {
+		return DeqOptions{}, fmt.Errorf("getDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	var visibility C.dpiVisibility
+	if C.dpiDeqOptions_getVisibility(&opts, &visibility) == C.DPI_FAILURE {
+		return DeqOptions{}, fmt.Errorf("getDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	var wait C.uint32_t
+	if C.dpiDeqOptions_getWait(&opts, &wait) == C.DPI_FAILURE {
+		return DeqOptions{}, fmt.Errorf("getDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+
+	return DeqOptions{
+		Condition:      C.GoStringN(cCond, C.int(condLen)),
+		Correlation:    C.GoStringN(cCorr, C.int(corrLen)),
+		MsgID:          C.GoBytes(unsafe.Pointer(cMsgID), C.int(msgIDLen)),
+		Mode:           DeqMode(mode),
+		Navigation:     DeqNavigation(nav),
+		Visibility:     DeqVisibility(visibility),
+		Wait:           time.Duration(wait) * time.Second,
+		MaxInlineBytes: Q.maxInlineBytes,
+	}, nil
+}
+
+// SetDeqOptions applies the given dequeue options to the queue, affecting
+// every subsequent Dequeue call. Use Condition, Correlation and MsgID to
+// filter which messages a Dequeue call is allowed to return.
+func (Q *Queue) SetDeqOptions(opts DeqOptions) error {
+	var cOpts C.dpiDeqOptions
+	if C.dpiQueue_getDeqOptions(Q.dpiQueue, &cOpts) == C.DPI_FAILURE {
+		return fmt.Errorf("setDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	if opts.Condition != "" {
+		cCond := C.CString(opts.Condition)
+		defer C.free(unsafe.Pointer(cCond))
+		C.dpiDeqOptions_setCondition(&cOpts, cCond, C.uint32_t(len(opts.Condition)))
+	}
+	if opts.Correlation != "" {
+		cCorr := C.CString(opts.Correlation)
+		defer C.free(unsafe.Pointer(cCorr))
+		C.dpiDeqOptions_setCorrelation(&cOpts, cCorr, C.uint32_t(len(opts.Correlation)))
+	}
+	if len(opts.MsgID) != 0 {
+		C.dpiDeqOptions_setMsgId(&cOpts, (*C.char)(unsafe.Pointer(&opts.MsgID[0])), C.uint32_t(len(opts.MsgID)))
+	}
+	if C.dpiDeqOptions_setMode(&cOpts, C.uint32_t(opts.Mode)) == C.DPI_FAILURE {
+		return fmt.Errorf("setDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	if C.dpiDeqOptions_setNavigation(&cOpts, C.dpiDeqNavigation(opts.Navigation)) == C.DPI_FAILURE {
+		return fmt.Errorf("setDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	if C.dpiDeqOptions_setVisibility(&cOpts, C.dpiVisibility(opts.Visibility)) == C.DPI_FAILURE {
+		return fmt.Errorf("setDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	if C.dpiDeqOptions_setWait(&cOpts, C.uint32_t(opts.Wait/time.Second)) == C.DPI_FAILURE {
+		return fmt.Errorf("setDeqOptions %q: %w", Q.name, Q.conn.getError())
+	}
+	if opts.MaxInlineBytes != 0 {
+		Q.maxInlineBytes = opts.MaxInlineBytes
+	}
+	return nil
+}
+
+// MaxBatchSize is the largest number of messages Enqueue or Dequeue will
+// exchange with the server in a single dpiQueue_enqMany/deqMany
+// round-trip; this mirrors the limit ODPI-C imposes on array operations.
+const MaxBatchSize = 1000
+
+// Enqueue enqueues the given messages onto the queue. Messages are sent
+// to the server in batches of at most MaxBatchSize, using a single
+// dpiQueue_enqMany round-trip per batch, instead of one round-trip per
+// message.
+func (Q *Queue) Enqueue(messages []Message) error {
+	Q.mu.Lock()
+	defer Q.mu.Unlock()
+	for len(messages) > 0 {
+		batch := messages
+		if len(batch) > MaxBatchSize {
+			batch = batch[:MaxBatchSize]
+		}
+		messages = messages[len(batch):]
+
+		props := make([]*C.dpiMsgProps, len(batch))
+		for i, m := range batch {
+			p, err := Q.newMsgProps(m)
+			if err != nil {
+				for _, p := range props[:i] {
+					C.dpiMsgProps_release(p)
+				}
+				return fmt.Errorf("enqueue: %w", err)
+			}
+			props[i] = p
+		}
+		rc := C.dpiQueue_enqMany(Q.dpiQueue, C.uint32_t(len(props)), &props[0])
+		for i, p := range props {
+			if rc != C.DPI_FAILURE {
+				var msgID *C.char
+				var msgIDLen C.uint32_t
+				if C.dpiMsgProps_getMsgId(p, &msgID, &msgIDLen) != C.DPI_FAILURE && msgIDLen > 0 {
+					batch[i].MsgID = C.GoBytes(unsafe.Pointer(msgID), C.int(msgIDLen))
+				}
+			}
+			C.dpiMsgProps_release(p)
+		}
+		if rc == C.DPI_FAILURE {
+			return fmt.Errorf("enqueue: %w", Q.conn.getError())
+		}
+	}
+	return nil
+}
+
+// Dequeue dequeues up to len(messages) messages into the given slice, in
+// batches of at most MaxBatchSize fetched per dpiQueue_deqMany
+// round-trip, and returns the number of messages actually dequeued.
+// Use SetDeqOptions to restrict which messages are eligible, e.g. by
+// DeqOptions.Correlation or DeqOptions.MsgID.
+func (Q *Queue) Dequeue(messages []Message) (int, error) {
+	Q.mu.Lock()
+	defer Q.mu.Unlock()
+	var n int
+	for n < len(messages) {
+		want := len(messages) - n
+		if want > MaxBatchSize {
+			want = MaxBatchSize
+		}
+		props := make([]*C.dpiMsgProps, want)
+		count := C.uint32_t(want)
+		rc := C.dpiQueue_deqMany(Q.dpiQueue, &count, &props[0])
+		if rc == C.DPI_FAILURE {
+			return n, fmt.Errorf("dequeue: %w", Q.conn.getError())
+		}
+		if count == 0 {
+			break
+		}
+		for i := 0; i < int(count); i++ {
+			m, err := Q.fromMsgProps(props[i])
+			C.dpiMsgProps_release(props[i])
+			if err != nil {
+				return n, fmt.Errorf("dequeue[%d]: %w", n, err)
+			}
+			messages[n] = m
+			n++
+		}
+		if int(count) < want {
+			break
+		}
+	}
+	return n, nil
+}
+
+func (Q *Queue) newMsgProps(m Message) (*C.dpiMsgProps, error) {
+	var props *C.dpiMsgProps
+	if C.dpiConn_newMsgProps(Q.conn.dpiConn, &props) == C.DPI_FAILURE {
+		return nil, Q.conn.getError()
+	}
+	if m.Raw != nil {
+		var p *C.char
+		if len(m.Raw) != 0 {
+			p = (*C.char)(unsafe.Pointer(&m.Raw[0]))
+		}
+		if C.dpiMsgProps_setPayloadBytes(props, p, C.uint32_t(len(m.Raw))) == C.DPI_FAILURE {
+			C.dpiMsgProps_release(props)
+			return nil, Q.conn.getError()
+		}
+	} else if m.Object != nil {
+		if C.dpiMsgProps_setPayloadObject(props, m.Object.dpiObject) == C.DPI_FAILURE {
+			C.dpiMsgProps_release(props)
+			return nil, Q.conn.getError()
+		}
+	} else if m.JSON != nil {
+		if err := Q.setPayloadJSON(props, m.JSON); err != nil {
+			C.dpiMsgProps_release(props)
+			return nil, err
+		}
+	}
+	if m.Correlation != "" {
+		cCorr := C.CString(m.Correlation)
+		defer C.free(unsafe.Pointer(cCorr))
+		C.dpiMsgProps_setCorrelation(props, cCorr, C.uint32_t(len(m.Correlation)))
+	}
+	if m.Priority != 0 {
+		C.dpiMsgProps_setPriority(props, C.int32_t(m.Priority))
+	}
+	if m.Delay != 0 {
+		C.dpiMsgProps_setDelay(props, C.int32_t(m.Delay/time.Second))
+	}
+	if m.Expiration != 0 {
+		C.dpiMsgProps_setExpiration(props, C.int32_t(m.Expiration/time.Second))
+	}
+	if m.ExceptionQ != "" {
+		cExcQ := C.CString(m.ExceptionQ)
+		defer C.free(unsafe.Pointer(cExcQ))
+		C.dpiMsgProps_setExceptionQ(props, cExcQ, C.uint32_t(len(m.ExceptionQ)))
+	}
+	if len(m.Recipients) != 0 {
+		recipients := make([]C.dpiMsgRecipient, len(m.Recipients))
+		for i, name := range m.Recipients {
+			cName := C.CString(name)
+			defer C.free(unsafe.Pointer(cName))
+			recipients[i].name = cName
+			recipients[i].nameLength = C.uint32_t(len(name))
+		}
+		if C.dpiMsgProps_setRecipients(props, &recipients[0], C.uint32_t(len(recipients))) == C.DPI_FAILURE {
+			C.dpiMsgProps_release(props)
+			return nil, fmt.Errorf("newMsgProps: setRecipients: %w", Q.conn.getError())
+		}
+	}
+	return props, nil
+}
+
+func (Q *Queue) fromMsgProps(props *C.dpiMsgProps) (Message, error) {
+	var m Message
+	if Q.isJSON {
+		raw, err := Q.getPayloadJSON(props)
+		if err != nil {
+			return m, err
+		}
+		m.JSON = raw
+	} else {
+		var payload *C.dpiObject
+		var bytes *C.char
+		var bytesLen C.uint32_t
+		if C.dpiMsgProps_getPayload(props, &payload, &bytes, &bytesLen) == C.DPI_FAILURE {
+			return m, Q.conn.getError()
+		}
+		if payload != nil {
+			obj, err := Q.payloadObjectType.fromDpiObject(payload)
+			if err != nil {
+				return m, err
+			}
+			m.Object = obj
+		} else if bytesLen > 0 {
+			m.Raw = C.GoBytes(unsafe.Pointer(bytes), C.int(bytesLen))
+		}
+	}
+
+	var cCorr *C.char
+	var corrLen C.uint32_t
+	C.dpiMsgProps_getCorrelation(props, &cCorr, &corrLen)
+	if corrLen > 0 {
+		m.Correlation = C.GoStringN(cCorr, C.int(corrLen))
+	}
+
+	var msgID *C.char
+	var msgIDLen C.uint32_t
+	C.dpiMsgProps_getMsgId(props, &msgID, &msgIDLen)
+	if msgIDLen > 0 {
+		m.MsgID = C.GoBytes(unsafe.Pointer(msgID), C.int(msgIDLen))
+	}
+
+	var priority C.int32_t
+	C.dpiMsgProps_getPriority(props, &priority)
+	m.Priority = int32(priority)
+
+	return m, nil
+}
@@ -0,0 +1,296 @@
+// Copyright 2026 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+/*
+#include <stdlib.h>
+#include "dpiImpl.h"
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"unsafe"
+)
+
+// WithJSONPayload marks the Queue created by NewQueue as bound to a JSON
+// payload queue (DBMS_AQADM.CREATE_QUEUE_TABLE with payload type JSON,
+// requires Oracle 21c or later), instead of RAW or an object type.
+//
+// Pass "" as NewQueue's payloadObjectType together with this option.
+func WithJSONPayload() QueueOption {
+	return func(Q *Queue) { Q.isJSON = true }
+}
+
+// EnqueueValue marshals v to JSON and enqueues it as a single message,
+// for use with a Queue created with WithJSONPayload.
+func (Q *Queue) EnqueueValue(v interface{}) error {
+	raw, ok := v.(json.RawMessage)
+	if !ok {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("enqueue JSON: marshal: %w", err)
+		}
+		raw = json.RawMessage(b)
+	}
+	return Q.Enqueue([]Message{{JSON: raw}})
+}
+
+// DequeueValue dequeues a single JSON message and unmarshals it into dest,
+// for use with a Queue created with WithJSONPayload. It returns false if
+// no message was available.
+func (Q *Queue) DequeueValue(dest interface{}) (bool, error) {
+	msgs := make([]Message, 1)
+	n, err := Q.Dequeue(msgs)
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+	raw := msgs[0].JSON
+	if len(raw) == 0 {
+		raw = json.RawMessage("null")
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		return false, fmt.Errorf("dequeue JSON: unmarshal: %w", err)
+	}
+	return true, nil
+}
+
+// setPayloadJSON sets props' payload to raw, by unmarshaling it into a Go
+// value and re-encoding that as a dpiJsonNode tree, ODPI-C's own
+// in-memory JSON representation (dpiJson_setValue/dpiJson_getValue);
+// ODPI-C has no text-parsing entry point of its own, unlike the higher
+// level LOB/object APIs.
+func (Q *Queue) setPayloadJSON(props *C.dpiMsgProps, raw json.RawMessage) error {
+	var v interface{}
+	if len(raw) != 0 {
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("enqueue JSON: %w", err)
+		}
+	}
+
+	var dj *C.dpiJson
+	if C.dpiConn_newJson(Q.conn.dpiConn, &dj) == C.DPI_FAILURE {
+		return Q.conn.getError()
+	}
+	defer C.dpiJson_release(dj)
+
+	node, free, err := newJSONNode(v)
+	if err != nil {
+		return fmt.Errorf("enqueue JSON: %w", err)
+	}
+	defer free()
+
+	if C.dpiJson_setValue(dj, node) == C.DPI_FAILURE {
+		return Q.conn.getError()
+	}
+	if C.dpiMsgProps_setPayloadJson(props, dj) == C.DPI_FAILURE {
+		return Q.conn.getError()
+	}
+	return nil
+}
+
+// getPayloadJSON reads props' JSON payload back into a json.RawMessage,
+// walking the dpiJsonNode tree dpiJson_getValue returns and
+// re-marshaling it with encoding/json.
+func (Q *Queue) getPayloadJSON(props *C.dpiMsgProps) (json.RawMessage, error) {
+	var dj *C.dpiJson
+	if C.dpiMsgProps_getPayloadJson(props, &dj) == C.DPI_FAILURE {
+		return nil, Q.conn.getError()
+	}
+	if dj == nil {
+		return json.RawMessage("null"), nil
+	}
+	var node *C.dpiJsonNode
+	if C.dpiJson_getValue(dj, C.DPI_JSON_OPT_DEFAULT, &node) == C.DPI_FAILURE {
+		return nil, Q.conn.getError()
+	}
+	v, err := jsonNodeToGoValue(node)
+	if err != nil {
+		return nil, fmt.Errorf("dequeue JSON: %w", err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("dequeue JSON: %w", err)
+	}
+	return json.RawMessage(b), nil
+}
+
+// newJSONNode encodes v (as produced by json.Unmarshal into interface{}:
+// nil, bool, float64, string, []interface{} or map[string]interface{})
+// into a dpiJsonNode tree dpiJson_setValue can consume, allocating the
+// C memory the tree needs. The returned free func releases all of it and
+// must be called once the dpiJson_setValue call using it has returned.
+func newJSONNode(v interface{}) (*C.dpiJsonNode, func(), error) {
+	var frees []func()
+	node := (*C.dpiJsonNode)(C.malloc(C.size_t(unsafe.Sizeof(C.dpiJsonNode{}))))
+	frees = append(frees, func() { C.free(unsafe.Pointer(node)) })
+	free := func() {
+		for i := len(frees) - 1; i >= 0; i-- {
+			frees[i]()
+		}
+	}
+	if err := fillJSONNode(node, v, &frees); err != nil {
+		free()
+		return nil, nil, err
+	}
+	return node, free, nil
+}
+
+func fillJSONNode(node *C.dpiJsonNode, v interface{}, frees *[]func()) error {
+	switch val := v.(type) {
+	case nil:
+		node.oracleTypeNum = C.DPI_ORACLE_TYPE_NONE
+		node.nativeTypeNum = C.DPI_NATIVE_TYPE_NULL
+	case bool:
+		node.oracleTypeNum = C.DPI_ORACLE_TYPE_BOOLEAN
+		node.nativeTypeNum = C.DPI_NATIVE_TYPE_BOOLEAN
+		*(*C.int)(unsafe.Pointer(&node.value)) = boolToCInt(val)
+	case float64:
+		node.oracleTypeNum = C.DPI_ORACLE_TYPE_NUMBER
+		node.nativeTypeNum = C.DPI_NATIVE_TYPE_DOUBLE
+		*(*C.double)(unsafe.Pointer(&node.value)) = C.double(val)
+	case json.Number:
+		f, err := val.Float64()
+		if err != nil {
+			return fmt.Errorf("json number %q: %w", val, err)
+		}
+		return fillJSONNode(node, f, frees)
+	case string:
+		node.oracleTypeNum = C.DPI_ORACLE_TYPE_VARCHAR
+		node.nativeTypeNum = C.DPI_NATIVE_TYPE_BYTES
+		cStr := C.CString(val)
+		*frees = append(*frees, func() { C.free(unsafe.Pointer(cStr)) })
+		setBytesValue(node, cStr, C.uint32_t(len(val)))
+	case []interface{}:
+		node.oracleTypeNum = C.DPI_ORACLE_TYPE_JSON_ARRAY
+		node.nativeTypeNum = C.DPI_NATIVE_TYPE_JSON_ARRAY
+		arr := (*C.dpiJsonArray)(unsafe.Pointer(&node.value))
+		arr.numElements = C.uint32_t(len(val))
+		if len(val) == 0 {
+			arr.elements = nil
+			return nil
+		}
+		elems := (*C.dpiJsonNode)(C.malloc(C.size_t(len(val)) * C.size_t(unsafe.Sizeof(C.dpiJsonNode{}))))
+		*frees = append(*frees, func() { C.free(unsafe.Pointer(elems)) })
+		arr.elements = elems
+		elemSlice := unsafe.Slice(elems, len(val))
+		for i, e := range val {
+			if err := fillJSONNode(&elemSlice[i], e, frees); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		node.oracleTypeNum = C.DPI_ORACLE_TYPE_JSON_OBJECT
+		node.nativeTypeNum = C.DPI_NATIVE_TYPE_JSON_OBJECT
+		obj := (*C.dpiJsonObject)(unsafe.Pointer(&node.value))
+		obj.numFields = C.uint32_t(len(val))
+		if len(val) == 0 {
+			obj.fieldNames = nil
+			obj.fieldNameLengths = nil
+			obj.fields = nil
+			return nil
+		}
+		names := make([]string, 0, len(val))
+		for k := range val {
+			names = append(names, k)
+		}
+		cNames := (**C.char)(C.malloc(C.size_t(len(names)) * C.size_t(unsafe.Sizeof((*C.char)(nil)))))
+		*frees = append(*frees, func() { C.free(unsafe.Pointer(cNames)) })
+		cLens := (*C.uint32_t)(C.malloc(C.size_t(len(names)) * C.size_t(unsafe.Sizeof(C.uint32_t(0)))))
+		*frees = append(*frees, func() { C.free(unsafe.Pointer(cLens)) })
+		fields := (*C.dpiJsonNode)(C.malloc(C.size_t(len(names)) * C.size_t(unsafe.Sizeof(C.dpiJsonNode{}))))
+		*frees = append(*frees, func() { C.free(unsafe.Pointer(fields)) })
+		cNameSlice := unsafe.Slice(cNames, len(names))
+		cLenSlice := unsafe.Slice(cLens, len(names))
+		fieldSlice := unsafe.Slice(fields, len(names))
+		for i, name := range names {
+			cName := C.CString(name)
+			*frees = append(*frees, func() { C.free(unsafe.Pointer(cName)) })
+			cNameSlice[i] = cName
+			cLenSlice[i] = C.uint32_t(len(name))
+			if err := fillJSONNode(&fieldSlice[i], val[name], frees); err != nil {
+				return err
+			}
+		}
+		obj.fieldNames = cNames
+		obj.fieldNameLengths = cLens
+		obj.fields = fields
+	default:
+		return fmt.Errorf("unsupported JSON value type %T", v)
+	}
+	return nil
+}
+
+// jsonNodeToGoValue is fillJSONNode's inverse, decoding a dpiJsonNode
+// tree dpiJson_getValue returned back into a plain Go value suitable for
+// encoding/json.Marshal.
+func jsonNodeToGoValue(node *C.dpiJsonNode) (interface{}, error) {
+	switch node.oracleTypeNum {
+	case C.DPI_ORACLE_TYPE_NONE:
+		return nil, nil
+	case C.DPI_ORACLE_TYPE_BOOLEAN:
+		return *(*C.int)(unsafe.Pointer(&node.value)) != 0, nil
+	case C.DPI_ORACLE_TYPE_NUMBER:
+		return float64(*(*C.double)(unsafe.Pointer(&node.value))), nil
+	case C.DPI_ORACLE_TYPE_VARCHAR, C.DPI_ORACLE_TYPE_CHAR:
+		p, n := bytesValue(node)
+		return C.GoStringN(p, C.int(n)), nil
+	case C.DPI_ORACLE_TYPE_JSON_ARRAY:
+		arr := (*C.dpiJsonArray)(unsafe.Pointer(&node.value))
+		out := make([]interface{}, int(arr.numElements))
+		elemSlice := unsafe.Slice(arr.elements, int(arr.numElements))
+		for i := range out {
+			v, err := jsonNodeToGoValue(&elemSlice[i])
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	case C.DPI_ORACLE_TYPE_JSON_OBJECT:
+		obj := (*C.dpiJsonObject)(unsafe.Pointer(&node.value))
+		n := int(obj.numFields)
+		out := make(map[string]interface{}, n)
+		names := unsafe.Slice(obj.fieldNames, n)
+		lens := unsafe.Slice(obj.fieldNameLengths, n)
+		fields := unsafe.Slice(obj.fields, n)
+		for i := 0; i < n; i++ {
+			v, err := jsonNodeToGoValue(&fields[i])
+			if err != nil {
+				return nil, err
+			}
+			out[C.GoStringN(names[i], C.int(lens[i]))] = v
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported dpiJsonNode.oracleTypeNum %d", node.oracleTypeNum)
+	}
+}
+
+func boolToCInt(b bool) C.int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// setBytesValue/bytesValue isolate the one spot that pokes directly at
+// dpiDataBuffer's asBytes member (a dpiBytes{ptr,length,encoding}), since
+// cgo has no way to express "union field" access beyond casting through
+// unsafe.Pointer.
+func setBytesValue(node *C.dpiJsonNode, p *C.char, length C.uint32_t) {
+	b := (*C.dpiBytes)(unsafe.Pointer(&node.value))
+	b.ptr = p
+	b.length = length
+}
+
+func bytesValue(node *C.dpiJsonNode) (*C.char, C.uint32_t) {
+	b := (*C.dpiBytes)(unsafe.Pointer(&node.value))
+	return b.ptr, b.length
+}
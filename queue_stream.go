@@ -0,0 +1,108 @@
+// Copyright 2026 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxInlineBytes is the default EnqOptions.MaxInlineBytes /
+// DeqOptions.MaxInlineBytes: payloads up to this size travel inline as
+// plain Raw bytes; larger ones are streamed through a temporary BLOB, so
+// neither producer nor consumer has to hold the whole payload in memory.
+const DefaultMaxInlineBytes = 1 << 20 // 1MiB
+
+// StreamPayloadAttr is the attribute name EnqueueStream and OpenReader
+// use to carry an over-threshold payload: the Queue must have been
+// created (via NewQueue's payloadObjectType) with an object type that
+// has exactly one attribute named DATA of type BLOB, e.g.:
+//
+//	CREATE TYPE my_stream_payload AS OBJECT (data BLOB)
+//
+// A plain RAW queue has nowhere to put a LOB, so EnqueueStream on one
+// errors out once the payload exceeds MaxInlineBytes rather than
+// attempting anything lossy.
+const StreamPayloadAttr = "DATA"
+
+// EnqueueStream reads r to completion and enqueues it as a single
+// message. Payloads up to the queue's MaxInlineBytes (see
+// SetEnqOptions; DefaultMaxInlineBytes if unset) travel inline as a
+// plain Raw message. Larger ones are written, in bounded chunks, into a
+// temporary BLOB (DBMS_LOB.createtemporary, the same mechanism
+// TestLOBAppend exercises for LOB OUT binds) that is then enqueued as
+// the DATA attribute of an object payload message: Oracle copies a
+// temporary LOB's contents into the real LOB column of the queue table
+// as part of enqueuing the object, so the result is a normal, permanent
+// message LOB, not a dangling session-local handle. This requires the
+// Queue to have been created with an object payload type shaped as
+// StreamPayloadAttr documents; see its doc comment.
+func (Q *Queue) EnqueueStream(r io.Reader) error {
+	max := Q.maxInlineBytes
+	if max == 0 {
+		max = DefaultMaxInlineBytes
+	}
+
+	head, err := io.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return fmt.Errorf("enqueueStream: %w", err)
+	}
+	if int64(len(head)) <= max {
+		return Q.Enqueue([]Message{{Raw: head}})
+	}
+	if Q.payloadObjectType == nil {
+		return fmt.Errorf("enqueueStream: payload of %d bytes exceeds MaxInlineBytes (%d) but %q has no object payload type to stream a LOB through; see StreamPayloadAttr", len(head), max, Q.name)
+	}
+
+	lob, err := Q.conn.newTemporaryBLOB()
+	if err != nil {
+		return fmt.Errorf("enqueueStream: %w", err)
+	}
+	defer lob.Close()
+	if _, err := lob.Write(head); err != nil {
+		return fmt.Errorf("enqueueStream: %w", err)
+	}
+	if _, err := io.Copy(lob, r); err != nil {
+		return fmt.Errorf("enqueueStream: %w", err)
+	}
+
+	obj, err := Q.payloadObjectType.NewObject()
+	if err != nil {
+		return fmt.Errorf("enqueueStream: %w", err)
+	}
+	defer obj.Close()
+	if err := obj.Set(StreamPayloadAttr, lob); err != nil {
+		return fmt.Errorf("enqueueStream: set %s: %w", StreamPayloadAttr, err)
+	}
+	return Q.Enqueue([]Message{{Object: obj}})
+}
+
+// OpenReader returns an io.ReadCloser over m's payload, transparently
+// covering both a plain inline Raw payload and an object payload shaped
+// per StreamPayloadAttr (as produced by EnqueueStream), so callers never
+// need to know which path a given message took.
+func (m Message) OpenReader() (io.ReadCloser, error) {
+	if m.Object == nil {
+		return io.NopCloser(bytes.NewReader(m.Raw)), nil
+	}
+	v, err := m.Object.Get(StreamPayloadAttr)
+	if err != nil {
+		return nil, fmt.Errorf("openReader: get %s: %w", StreamPayloadAttr, err)
+	}
+	switch lob := v.(type) {
+	case *DirectLob:
+		return lob, nil
+	case Lob:
+		dl, err := lob.Hijack()
+		if err != nil {
+			return nil, fmt.Errorf("openReader: %w", err)
+		}
+		return dl, nil
+	default:
+		return nil, fmt.Errorf("openReader: attribute %s is a %T, not a LOB", StreamPayloadAttr, v)
+	}
+}
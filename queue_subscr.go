@@ -0,0 +1,210 @@
+// Copyright 2026 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+/*
+#include <stdlib.h>
+#include "dpiImpl.h"
+
+extern void godrorSubscrCallback(void *context, dpiSubscrMessage *message);
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"runtime/cgo"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// Subscription delivers Messages enqueued onto a Queue to a Go callback,
+// using OCI's Change Notification / AQ notification (DBMS_AQ.REGISTER),
+// without holding a goroutine blocked in a synchronous Dequeue.
+//
+// Obtain one with Queue.Subscribe, and release it with Close once it is
+// no longer needed.
+type Subscription struct {
+	queue     *Queue
+	handler   func(Message) error
+	dpiSubscr *C.dpiSubscr
+	handle    cgo.Handle
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	closed    chan struct{}
+
+	// invalidated is signalled by godrorSubscrCallback when OCI reports
+	// the subscription was deregistered out from under it (e.g. the
+	// session it rode in on was killed), so reconnectOnDrop can
+	// re-register it.
+	invalidated chan struct{}
+}
+
+// Subscribe registers handler to be called asynchronously, on the
+// driver's internal OCI event thread, for every message enqueued onto Q.
+// handler errors are discarded and do not stop delivery of subsequent
+// messages; to stop receiving, call Subscription.Close.
+//
+// When OCI reports the subscription deregistered (ORA-24028-style
+// invalidation), the returned Subscription re-registers itself on Q's
+// own connection, retrying once a second until that succeeds or Close
+// is called. It does not open a replacement connection, so a session
+// that is gone for good is not recovered. Call Close to stop retrying
+// and release all resources.
+func (Q *Queue) Subscribe(ctx context.Context, handler func(Message) error) (*Subscription, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("subscribe %q: nil handler", Q.name)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	S := &Subscription{
+		queue:       Q,
+		handler:     handler,
+		cancel:      cancel,
+		closed:      make(chan struct{}),
+		invalidated: make(chan struct{}, 1),
+	}
+	S.handle = cgo.NewHandle(S)
+
+	if err := S.register(); err != nil {
+		S.handle.Delete()
+		cancel()
+		return nil, err
+	}
+
+	go S.reconnectOnDrop(ctx)
+	return S, nil
+}
+
+// register (re)creates the underlying dpiSubscr. The callback context
+// passed to ODPI-C is S.handle's integer value, not a Go pointer: ODPI-C
+// retains that context across the lifetime of the subscription and hands
+// it back from a foreign (OCI event) thread, which a live Go pointer
+// isn't safe to survive - cgo.Handle gives it an opaque, GC-invisible
+// integer to carry instead.
+func (S *Subscription) register() error {
+	Q := S.queue
+	var params C.dpiSubscrCreateParams
+	if C.dpiContext_initSubscrCreateParams(Q.conn.dpiContext, &params) == C.DPI_FAILURE {
+		return fmt.Errorf("subscribe %q: %w", Q.name, Q.conn.getError())
+	}
+	params.subscrNamespace = C.DPI_SUBSCR_NAMESPACE_AQ
+	cName := C.CString(Q.name)
+	defer C.free(unsafe.Pointer(cName))
+	params.name = cName
+	params.nameLength = C.uint32_t(len(Q.name))
+	params.callback = (C.dpiSubscrCallback)(C.godrorSubscrCallback)
+	params.callbackContext = unsafe.Pointer(uintptr(S.handle))
+
+	var dpiSubscr *C.dpiSubscr
+	if C.dpiConn_newSubscription(Q.conn.dpiConn, &params, &dpiSubscr, nil) == C.DPI_FAILURE {
+		return fmt.Errorf("subscribe %q: %w", Q.name, Q.conn.getError())
+	}
+	if S.dpiSubscr != nil {
+		// A reconnect: the old handle is already gone server-side (that's
+		// why we're here), but its client-side resources still need
+		// releasing, or every re-registration leaks one.
+		C.dpiSubscr_release(S.dpiSubscr)
+	}
+	S.dpiSubscr = dpiSubscr
+	return nil
+}
+
+// reconnectOnDrop watches for the subscription being invalidated by a
+// dropped session (e.g. a DBA killing the session) and re-registers it,
+// so that callers don't silently stop receiving messages. It retries on
+// the Queue's connection with a short, fixed backoff of one second.
+func (S *Subscription) reconnectOnDrop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-S.invalidated:
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			if err := S.register(); err == nil {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// deliver is called from godrorSubscrCallback with the dequeued Message;
+// it runs on the OCI notification thread that invoked that callback.
+func (S *Subscription) deliver(m Message) {
+	select {
+	case <-S.closed:
+		return
+	default:
+	}
+	if err := S.handler(m); err != nil {
+		// Errors are the caller's responsibility to log; Subscription has
+		// no logger of its own, mirroring Queue's error conventions.
+		_ = err
+	}
+}
+
+//export godrorSubscrCallback
+func godrorSubscrCallback(context unsafe.Pointer, message *C.dpiSubscrMessage) {
+	if message == nil {
+		return
+	}
+	// h.Value() panics on a handle Close already deleted rather than
+	// returning a zero value, so Close must unsubscribe (which blocks
+	// until no callback for this subscription is in flight) before it
+	// deletes the handle, not after - see Subscription.Close.
+	S := cgo.Handle(uintptr(context)).Value().(*Subscription)
+	if message.type_ == C.DPI_EVENT_DEREG {
+		select {
+		case S.invalidated <- struct{}{}:
+		default:
+		}
+		return
+	}
+	if message.numQueryChanges == 0 && message.type_ != C.DPI_EVENT_DEQ && message.type_ != C.DPI_EVENT_AQ {
+		return
+	}
+	msgs := make([]Message, 1)
+	if n, err := S.queue.Dequeue(msgs); err == nil {
+		for _, m := range msgs[:n] {
+			S.deliver(m)
+		}
+	}
+}
+
+// Close unregisters the subscription and stops delivering messages.
+// Close is idempotent.
+func (S *Subscription) Close() error {
+	var err error
+	S.closeOnce.Do(func() {
+		close(S.closed)
+		S.cancel()
+		// dpiConn_unsubscribe must run, and the notification thread must
+		// be done calling godrorSubscrCallback, before the handle is
+		// deleted: that callback looks the handle up on every
+		// invocation, and cgo.Handle.Value panics rather than returning
+		// nil once Delete has run, so deleting first would let a
+		// callback still in flight crash the process.
+		if S.dpiSubscr != nil {
+			if C.dpiConn_unsubscribe(S.queue.conn.dpiConn, S.dpiSubscr) == C.DPI_FAILURE {
+				err = fmt.Errorf("unsubscribe %q: %w", S.queue.name, S.queue.conn.getError())
+			}
+			C.dpiSubscr_release(S.dpiSubscr)
+			S.dpiSubscr = nil
+		}
+		S.handle.Delete()
+	})
+	return err
+}
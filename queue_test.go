@@ -6,7 +6,10 @@
 package godror_test
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -102,6 +105,386 @@ func TestQueue(t *testing.T) {
 		t.Logf("got: %#v (%q)", m, string(m.Raw))
 	}
 }
+func TestQueueSubscribe(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("QueueSubscribe"), 30*time.Second)
+	defer cancel()
+	conn, err := testDb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	var user string
+	if err = conn.QueryRowContext(ctx, "SELECT USER FROM DUAL").Scan(&user); err != nil {
+		t.Fatal(err)
+	}
+
+	const qName = "TEST_Q_SUBSCR"
+	const qTblName = qName + "_TBL"
+	qry := `DECLARE
+		tbl CONSTANT VARCHAR2(61) := '` + user + "." + qTblName + `';
+		q CONSTANT VARCHAR2(61) := '` + user + "." + qName + `';
+	BEGIN
+		BEGIN SYS.DBMS_AQADM.stop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+		BEGIN SYS.DBMS_AQADM.drop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+		BEGIN SYS.DBMS_AQADM.drop_queue_table(tbl); EXCEPTION WHEN OTHERS THEN NULL; END;
+
+		SYS.DBMS_AQADM.CREATE_QUEUE_TABLE(tbl, 'RAW');
+		SYS.DBMS_AQADM.CREATE_QUEUE(q, tbl);
+		SYS.DBMS_AQADM.grant_queue_privilege('ENQUEUE', q, '` + user + `');
+		SYS.DBMS_AQADM.grant_queue_privilege('DEQUEUE', q, '` + user + `');
+		SYS.DBMS_AQADM.start_queue(q);
+	END;`
+	if _, err = conn.ExecContext(ctx, qry); err != nil {
+		if strings.Contains(err.Error(), "PLS-00201: identifier 'SYS.DBMS_AQADM' must be declared") {
+			t.Skip(err.Error())
+		}
+		t.Log(errors.Errorf("%s: %w", qry, err))
+	}
+	defer func() {
+		conn.ExecContext(
+			testContext("QueueSubscribe-drop"),
+			`DECLARE
+			tbl CONSTANT VARCHAR2(61) := USER||'.'||:1;
+			q CONSTANT VARCHAR2(61) := USER||'.'||:2;
+		BEGIN
+			BEGIN SYS.DBMS_AQADM.stop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+			BEGIN SYS.DBMS_AQADM.drop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+			BEGIN SYS.DBMS_AQADM.drop_queue_table(tbl); EXCEPTION WHEN OTHERS THEN NULL;
+		END;`,
+			qTblName, qName,
+		)
+	}()
+
+	q, err := godror.NewQueue(ctx, conn, qName, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	received := make(chan godror.Message, 1)
+	sub, err := q.Subscribe(ctx, func(m godror.Message) error {
+		received <- m
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	if err = q.Enqueue([]godror.Message{{Raw: []byte("async hello")}}); err != nil {
+		var ec interface{ Code() int }
+		if errors.As(err, &ec) && ec.Code() == 24444 {
+			t.Skip(err)
+		}
+		t.Fatal("enqueue:", err)
+	}
+
+	select {
+	case m := <-received:
+		if string(m.Raw) != "async hello" {
+			t.Errorf("got %q, wanted %q", m.Raw, "async hello")
+		}
+	case <-time.After(20 * time.Second):
+		t.Fatal("timed out waiting for subscription callback")
+	}
+
+	if err = sub.Close(); err != nil {
+		t.Error("close subscription:", err)
+	}
+}
+
+func TestQueueBatch(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("QueueBatch"), 30*time.Second)
+	defer cancel()
+	conn, err := testDb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	var user string
+	if err = conn.QueryRowContext(ctx, "SELECT USER FROM DUAL").Scan(&user); err != nil {
+		t.Fatal(err)
+	}
+
+	const qName = "TEST_Q_BATCH"
+	const qTblName = qName + "_TBL"
+	qry := `DECLARE
+		tbl CONSTANT VARCHAR2(61) := '` + user + "." + qTblName + `';
+		q CONSTANT VARCHAR2(61) := '` + user + "." + qName + `';
+	BEGIN
+		BEGIN SYS.DBMS_AQADM.stop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+		BEGIN SYS.DBMS_AQADM.drop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+		BEGIN SYS.DBMS_AQADM.drop_queue_table(tbl); EXCEPTION WHEN OTHERS THEN NULL; END;
+
+		SYS.DBMS_AQADM.CREATE_QUEUE_TABLE(tbl, 'RAW');
+		SYS.DBMS_AQADM.CREATE_QUEUE(q, tbl);
+		SYS.DBMS_AQADM.grant_queue_privilege('ENQUEUE', q, '` + user + `');
+		SYS.DBMS_AQADM.grant_queue_privilege('DEQUEUE', q, '` + user + `');
+		SYS.DBMS_AQADM.start_queue(q);
+	END;`
+	if _, err = conn.ExecContext(ctx, qry); err != nil {
+		if strings.Contains(err.Error(), "PLS-00201: identifier 'SYS.DBMS_AQADM' must be declared") {
+			t.Skip(err.Error())
+		}
+		t.Log(errors.Errorf("%s: %w", qry, err))
+	}
+	defer func() {
+		conn.ExecContext(
+			testContext("QueueBatch-drop"),
+			`DECLARE
+			tbl CONSTANT VARCHAR2(61) := USER||'.'||:1;
+			q CONSTANT VARCHAR2(61) := USER||'.'||:2;
+		BEGIN
+			BEGIN SYS.DBMS_AQADM.stop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+			BEGIN SYS.DBMS_AQADM.drop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+			BEGIN SYS.DBMS_AQADM.drop_queue_table(tbl); EXCEPTION WHEN OTHERS THEN NULL;
+		END;`,
+			qTblName, qName,
+		)
+	}()
+
+	q, err := godror.NewQueue(ctx, conn, qName, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	const n = 100
+	const correlation = "batch-test"
+	msgs := make([]godror.Message, n)
+	for i := range msgs {
+		msgs[i] = godror.Message{
+			Raw:         []byte(fmt.Sprintf("msg-%03d", i)),
+			Correlation: correlation,
+		}
+	}
+	if err = q.Enqueue(msgs); err != nil {
+		var ec interface{ Code() int }
+		if errors.As(err, &ec) && ec.Code() == 24444 {
+			t.Skip(err)
+		}
+		t.Fatal("enqueue:", err)
+	}
+
+	if err = q.SetDeqOptions(godror.DeqOptions{
+		Correlation: correlation,
+		Navigation:  godror.NavNextMsg, // next message, preserving enqueue order
+	}); err != nil {
+		t.Fatal("setDeqOptions:", err)
+	}
+
+	got := make([]godror.Message, n)
+	count, err := q.Dequeue(got)
+	if err != nil {
+		t.Fatal("dequeue:", err)
+	}
+	if count != n {
+		t.Fatalf("dequeued %d messages, wanted %d", count, n)
+	}
+	for i, m := range got[:count] {
+		want := fmt.Sprintf("msg-%03d", i)
+		if string(m.Raw) != want {
+			t.Errorf("message %d: got %q, wanted %q", i, m.Raw, want)
+		}
+		if m.Correlation != correlation {
+			t.Errorf("message %d: correlation got %q, wanted %q", i, m.Correlation, correlation)
+		}
+	}
+}
+
+func TestQueueJSON(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("QueueJSON"), 30*time.Second)
+	defer cancel()
+	conn, err := testDb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var version string
+	if err = conn.QueryRowContext(ctx, "SELECT version FROM product_component_version WHERE ROWNUM = 1").Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Split(version, ".")[0] < "21" {
+		t.Skip("JSON queues require Oracle 21c or later, got " + version)
+	}
+
+	var user string
+	if err = conn.QueryRowContext(ctx, "SELECT USER FROM DUAL").Scan(&user); err != nil {
+		t.Fatal(err)
+	}
+
+	const qName = "TEST_Q_JSON"
+	const qTblName = qName + "_TBL"
+	qry := `DECLARE
+		tbl CONSTANT VARCHAR2(61) := '` + user + "." + qTblName + `';
+		q CONSTANT VARCHAR2(61) := '` + user + "." + qName + `';
+	BEGIN
+		BEGIN SYS.DBMS_AQADM.stop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+		BEGIN SYS.DBMS_AQADM.drop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+		BEGIN SYS.DBMS_AQADM.drop_queue_table(tbl); EXCEPTION WHEN OTHERS THEN NULL; END;
+
+		SYS.DBMS_AQADM.CREATE_QUEUE_TABLE(tbl, 'JSON');
+		SYS.DBMS_AQADM.CREATE_QUEUE(q, tbl);
+		SYS.DBMS_AQADM.grant_queue_privilege('ENQUEUE', q, '` + user + `');
+		SYS.DBMS_AQADM.grant_queue_privilege('DEQUEUE', q, '` + user + `');
+		SYS.DBMS_AQADM.start_queue(q);
+	END;`
+	if _, err = conn.ExecContext(ctx, qry); err != nil {
+		if strings.Contains(err.Error(), "PLS-00201: identifier 'SYS.DBMS_AQADM' must be declared") {
+			t.Skip(err.Error())
+		}
+		t.Log(errors.Errorf("%s: %w", qry, err))
+	}
+	defer func() {
+		conn.ExecContext(
+			testContext("QueueJSON-drop"),
+			`DECLARE
+			tbl CONSTANT VARCHAR2(61) := USER||'.'||:1;
+			q CONSTANT VARCHAR2(61) := USER||'.'||:2;
+		BEGIN
+			BEGIN SYS.DBMS_AQADM.stop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+			BEGIN SYS.DBMS_AQADM.drop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+			BEGIN SYS.DBMS_AQADM.drop_queue_table(tbl); EXCEPTION WHEN OTHERS THEN NULL;
+		END;`,
+			qTblName, qName,
+		)
+	}()
+
+	q, err := godror.NewQueue(ctx, conn, qName, "", godror.WithJSONPayload())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	type doc struct {
+		Name string `json:"name"`
+		N    int    `json:"n"`
+	}
+	want := doc{Name: "árvíztűrő tükörfúrógép", N: 42}
+	if err = q.EnqueueValue(want); err != nil {
+		var ec interface{ Code() int }
+		if errors.As(err, &ec) && ec.Code() == 24444 {
+			t.Skip(err)
+		}
+		t.Fatal("enqueue:", err)
+	}
+
+	var got doc
+	ok, err := q.DequeueValue(&got)
+	if err != nil {
+		t.Fatal("dequeue:", err)
+	}
+	if !ok {
+		t.Fatal("no message dequeued")
+	}
+	if got != want {
+		t.Errorf("got %+v, wanted %+v", got, want)
+	}
+}
+
+func TestQueueStream(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("QueueStream"), 30*time.Second)
+	defer cancel()
+	conn, err := testDb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	var user string
+	if err = conn.QueryRowContext(ctx, "SELECT USER FROM DUAL").Scan(&user); err != nil {
+		t.Fatal(err)
+	}
+
+	const qName = "TEST_Q_STREAM"
+	const qTblName = qName + "_TBL"
+	const qTypName = qName + "_TYP"
+	conn.ExecContext(ctx, "DROP TYPE "+qTypName)
+
+	qry := `DECLARE
+		tbl CONSTANT VARCHAR2(61) := '` + user + "." + qTblName + `';
+		q CONSTANT VARCHAR2(61) := '` + user + "." + qName + `';
+		typ CONSTANT VARCHAR2(61) := '` + user + "." + qTypName + `';
+	BEGIN
+		BEGIN SYS.DBMS_AQADM.stop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+		BEGIN SYS.DBMS_AQADM.drop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+		BEGIN SYS.DBMS_AQADM.drop_queue_table(tbl); EXCEPTION WHEN OTHERS THEN NULL; END;
+		BEGIN EXECUTE IMMEDIATE 'DROP TYPE '||typ||' FORCE'; EXCEPTION WHEN OTHERS THEN NULL; END;
+
+		EXECUTE IMMEDIATE 'CREATE OR REPLACE TYPE '||typ||' IS OBJECT (` + godror.StreamPayloadAttr + ` BLOB)';
+		SYS.DBMS_AQADM.CREATE_QUEUE_TABLE(tbl, typ);
+		SYS.DBMS_AQADM.CREATE_QUEUE(q, tbl);
+		SYS.DBMS_AQADM.grant_queue_privilege('ENQUEUE', q, '` + user + `');
+		SYS.DBMS_AQADM.grant_queue_privilege('DEQUEUE', q, '` + user + `');
+		SYS.DBMS_AQADM.start_queue(q);
+	END;`
+	if _, err = conn.ExecContext(ctx, qry); err != nil {
+		if strings.Contains(err.Error(), "PLS-00201: identifier 'SYS.DBMS_AQADM' must be declared") {
+			t.Skip(err.Error())
+		}
+		t.Log(errors.Errorf("%s: %w", qry, err))
+	}
+	defer func() {
+		conn.ExecContext(
+			testContext("QueueStream-drop"),
+			`DECLARE
+			tbl CONSTANT VARCHAR2(61) := USER||'.'||:1;
+			q CONSTANT VARCHAR2(61) := USER||'.'||:2;
+			typ CONSTANT VARCHAR2(61) := USER||'.'||:3;
+		BEGIN
+			BEGIN SYS.DBMS_AQADM.stop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+			BEGIN SYS.DBMS_AQADM.drop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+			BEGIN SYS.DBMS_AQADM.drop_queue_table(tbl); EXCEPTION WHEN OTHERS THEN NULL; END;
+			BEGIN EXECUTE IMMEDIATE 'DROP TYPE '||typ||' FORCE'; EXCEPTION WHEN OTHERS THEN NULL; END;
+		END;`,
+			qTblName, qName, qTypName,
+		)
+	}()
+
+	q, err := godror.NewQueue(ctx, conn, qName, qTypName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	if err = q.SetEnqOptions(godror.EnqOptions{MaxInlineBytes: 16}); err != nil {
+		t.Fatal("setEnqOptions:", err)
+	}
+
+	want := bytes.Repeat([]byte("0123456789"), 100) // bigger than the 16-byte inline threshold
+	if err = q.EnqueueStream(bytes.NewReader(want)); err != nil {
+		var ec interface{ Code() int }
+		if errors.As(err, &ec) && ec.Code() == 24444 {
+			t.Skip(err)
+		}
+		t.Fatal("enqueueStream:", err)
+	}
+
+	msgs := make([]godror.Message, 1)
+	n, err := q.Dequeue(msgs)
+	if err != nil {
+		t.Fatal("dequeue:", err)
+	}
+	if n != 1 {
+		t.Fatal("no message dequeued")
+	}
+	rc, err := msgs[0].OpenReader()
+	if err != nil {
+		t.Fatal("openReader:", err)
+	}
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal("read:", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %d bytes, wanted %d bytes", len(got), len(want))
+	}
+}
+
 func TestQueueObject(t *testing.T) {
 	t.Parallel()
 	ctx, cancel := context.WithTimeout(testContext("QueueObject"), 30*time.Second)
@@ -241,3 +624,126 @@ func TestQueueObject(t *testing.T) {
 		t.Logf("got: %#v (%q)", m, string(m.Raw))
 	}
 }
+
+// TestQueueObjectSubscribe is TestQueueSubscribe's object-payload
+// counterpart: it exercises the godrorSubscrCallback -> Queue.Dequeue
+// path with an object message instead of a RAW one, since that path
+// decodes payloads differently (dpiMsgProps_getPayload yields a
+// *C.dpiObject, not bytes).
+func TestQueueObjectSubscribe(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("QueueObjectSubscribe"), 30*time.Second)
+	defer cancel()
+	conn, err := testDb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var user string
+	if err = conn.QueryRowContext(ctx, "SELECT USER FROM DUAL").Scan(&user); err != nil {
+		t.Fatal(err)
+	}
+
+	const qName = "TEST_QOBJ_SUB"
+	const qTblName = qName + "_TBL"
+	const qTypName = qName + "_TYP"
+	conn.ExecContext(ctx, "DROP TYPE "+qTypName)
+
+	qry := `DECLARE
+		tbl CONSTANT VARCHAR2(61) := '` + user + "." + qTblName + `';
+		q CONSTANT VARCHAR2(61) := '` + user + "." + qName + `';
+		typ CONSTANT VARCHAR2(61) := '` + user + "." + qTypName + `';
+	BEGIN
+		BEGIN SYS.DBMS_AQADM.stop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+		BEGIN SYS.DBMS_AQADM.drop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+		BEGIN SYS.DBMS_AQADM.drop_queue_table(tbl); EXCEPTION WHEN OTHERS THEN NULL; END;
+		BEGIN EXECUTE IMMEDIATE 'DROP TYPE '||typ||' FORCE'; EXCEPTION WHEN OTHERS THEN NULL; END;
+
+		EXECUTE IMMEDIATE 'CREATE OR REPLACE TYPE '||typ||' IS OBJECT (f_vc20 VARCHAR2(20))';
+		SYS.DBMS_AQADM.CREATE_QUEUE_TABLE(tbl, typ);
+		SYS.DBMS_AQADM.CREATE_QUEUE(q, tbl);
+		SYS.DBMS_AQADM.grant_queue_privilege('ENQUEUE', q, '` + user + `');
+		SYS.DBMS_AQADM.grant_queue_privilege('DEQUEUE', q, '` + user + `');
+		SYS.DBMS_AQADM.start_queue(q);
+	END;`
+	if _, err = conn.ExecContext(ctx, qry); err != nil {
+		if strings.Contains(err.Error(), "PLS-00201: identifier 'SYS.DBMS_AQADM' must be declared") {
+			t.Skip(err.Error())
+		}
+		t.Log(errors.Errorf("%s: %w", qry, err))
+	}
+	defer func() {
+		conn.ExecContext(
+			testContext("QueueObjectSubscribe-drop"),
+			`DECLARE
+			tbl CONSTANT VARCHAR2(61) := USER||'.'||:1;
+			q CONSTANT VARCHAR2(61) := USER||'.'||:2;
+			typ CONSTANT VARCHAR2(61) := USER||'.'||:3;
+		BEGIN
+			BEGIN SYS.DBMS_AQADM.stop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+			BEGIN SYS.DBMS_AQADM.drop_queue(q); EXCEPTION WHEN OTHERS THEN NULL; END;
+			BEGIN SYS.DBMS_AQADM.drop_queue_table(tbl); EXCEPTION WHEN OTHERS THEN NULL; END;
+			BEGIN EXECUTE IMMEDIATE 'DROP TYPE '||typ||' FORCE'; EXCEPTION WHEN OTHERS THEN NULL; END;
+		END;`,
+			qTblName, qName, qTypName,
+		)
+	}()
+
+	q, err := godror.NewQueue(ctx, conn, qName, qTypName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	oTyp, err := godror.GetObjectType(ctx, conn, qTypName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := oTyp.NewObject()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer obj.Close()
+	if err = obj.Set("F_VC20", "árvíztűrő"); err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan godror.Message, 1)
+	sub, err := q.Subscribe(ctx, func(m godror.Message) error {
+		received <- m
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	if err = q.Enqueue([]godror.Message{{Object: obj}}); err != nil {
+		var ec interface{ Code() int }
+		if errors.As(err, &ec) && ec.Code() == 24444 {
+			t.Skip(err)
+		}
+		t.Fatal("enqueue:", err)
+	}
+
+	select {
+	case m := <-received:
+		if m.Object == nil {
+			t.Fatal("got a message with no Object payload")
+		}
+		got, err := m.Object.Get("F_VC20")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "árvíztűrő"; got != want {
+			t.Errorf("got %q, wanted %q", got, want)
+		}
+	case <-time.After(20 * time.Second):
+		t.Fatal("timed out waiting for subscription callback")
+	}
+
+	if err = sub.Close(); err != nil {
+		t.Error("close subscription:", err)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// IsolationLevel values usable as database/sql's sql.TxOptions.Isolation,
+// on top of the driver.IsolationLevel the sql package already defines.
+const (
+	// LevelReadOnlySnapshot opens a read-only transaction pinned to the
+	// SCN current at BeginTx, via SET TRANSACTION READ ONLY. All
+	// statements in the transaction see a consistent snapshot as of that
+	// SCN, even as concurrent transactions commit - useful for holding
+	// LOB locators alive across multiple Fetch calls without them
+	// drifting out from under the reader (see TestStatWithLobs).
+	LevelReadOnlySnapshot = 100 + iota
+	// LevelSerializableSnapshot opens a SERIALIZABLE transaction, which
+	// like LevelReadOnlySnapshot pins reads to the BeginTx SCN, but also
+	// allows writes (failing with ORA-08177 on conflict).
+	LevelSerializableSnapshot
+)
+
+// TxOptions carries godror-specific transaction controls that
+// sql.TxOptions has no room for, namely pinning a transaction's read
+// snapshot to an explicit SCN rather than whatever is current at
+// BeginTx. Thread it through a *sql.DB/*sql.Conn's BeginTx with
+// ContextWithTxOptions, alongside sql.TxOptions.Isolation set to
+// LevelReadOnlySnapshot or LevelSerializableSnapshot.
+type TxOptions struct {
+	// SnapshotSCN, if nonzero, pins the transaction to this SCN (via
+	// "AS OF SCN" flashback query semantics) instead of the current one;
+	// obtain one to reuse from a prior transaction with TxSnapshotSCN.
+	SnapshotSCN uint64
+}
+
+type txOptionsCtxKey struct{}
+
+// ContextWithTxOptions returns a context carrying opts, for use with
+// (*sql.DB).BeginTx or (*sql.Conn).BeginTx:
+//
+//	ctx = godror.ContextWithTxOptions(ctx, godror.TxOptions{SnapshotSCN: scn})
+//	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: godror.LevelReadOnlySnapshot})
+func ContextWithTxOptions(ctx context.Context, opts TxOptions) context.Context {
+	return context.WithValue(ctx, txOptionsCtxKey{}, opts)
+}
+
+// BeginTx implements driver.ConnBeginTx, layering the SET TRANSACTION
+// statement LevelReadOnlySnapshot and LevelSerializableSnapshot need on
+// top of c's normal start-transaction path, so a *sql.DB/*sql.Conn
+// opened with one of those isolation levels gets a snapshot pinned to
+// the SCN current at BeginTx (or to opts.SnapshotSCN, via
+// ContextWithTxOptions) before the caller issues its first statement.
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	pinnedSCN, ok := ctx.Value(txOptionsCtxKey{}).(TxOptions)
+	pin := ok && pinnedSCN.SnapshotSCN != 0
+	switch driver.IsolationLevel(opts.Isolation) {
+	case LevelReadOnlySnapshot, LevelSerializableSnapshot:
+	default:
+		pin = false
+	}
+
+	// DBMS_FLASHBACK.ENABLE_AT_SYSTEM_CHANGE_NUMBER must run before the
+	// transaction starts: once it has, SET TRANSACTION READ ONLY/
+	// SERIALIZABLE has to be that transaction's very first statement, so
+	// there is no later point at which we could still issue it.
+	if pin {
+		enable := fmt.Sprintf("BEGIN DBMS_FLASHBACK.ENABLE_AT_SYSTEM_CHANGE_NUMBER(%d); END;", pinnedSCN.SnapshotSCN)
+		if _, err := c.ExecContext(ctx, enable, nil); err != nil {
+			return nil, fmt.Errorf("beginTx pin to SCN %d: %w", pinnedSCN.SnapshotSCN, err)
+		}
+	}
+
+	tx, err := c.beginTx(opts)
+	if err != nil {
+		if pin {
+			c.ExecContext(ctx, "BEGIN DBMS_FLASHBACK.DISABLE; END;", nil)
+		}
+		return nil, err
+	}
+	if err := beginTxSnapshot(ctx, c, opts); err != nil {
+		tx.Rollback()
+		if pin {
+			c.ExecContext(ctx, "BEGIN DBMS_FLASHBACK.DISABLE; END;", nil)
+		}
+		return nil, err
+	}
+	if !pin {
+		return tx, nil
+	}
+	// Flashback mode stays on for the whole transaction - it, not just
+	// the opening SET TRANSACTION, is what keeps every read pinned to
+	// SnapshotSCN - and is switched off only once the transaction ends.
+	return &flashbackPinnedTx{Tx: tx, c: c}, nil
+}
+
+// beginTxSnapshot issues the SET TRANSACTION statement
+// LevelReadOnlySnapshot and LevelSerializableSnapshot need, right after
+// the underlying OCI transaction has started, so every statement in it
+// observes the same SCN; it is a no-op for any other isolation level.
+//
+// Pinning to an explicit SnapshotSCN is handled by the caller (BeginTx)
+// before the transaction starts, via
+// DBMS_FLASHBACK.ENABLE_AT_SYSTEM_CHANGE_NUMBER, not a "SET TRANSACTION
+// ... AS OF SCN" clause - Oracle has no such syntax, only a per-query "AS
+// OF SCN" on SELECT.
+func beginTxSnapshot(ctx context.Context, c *conn, txOpts driver.TxOptions) error {
+	var ddl string
+	switch driver.IsolationLevel(txOpts.Isolation) {
+	case LevelReadOnlySnapshot:
+		ddl = "SET TRANSACTION READ ONLY"
+	case LevelSerializableSnapshot:
+		ddl = "SET TRANSACTION ISOLATION LEVEL SERIALIZABLE"
+	default:
+		return nil
+	}
+	if _, err := c.ExecContext(ctx, ddl, nil); err != nil {
+		return fmt.Errorf("beginTx %s: %w", ddl, err)
+	}
+	return nil
+}
+
+// flashbackPinnedTx wraps the driver.Tx of a transaction opened against
+// an explicit SnapshotSCN, switching DBMS_FLASHBACK off again once the
+// transaction ends instead of immediately after BeginTx - disabling it
+// any earlier would un-pin every statement the caller is about to run.
+type flashbackPinnedTx struct {
+	driver.Tx
+	c *conn
+}
+
+func (t *flashbackPinnedTx) Commit() error {
+	err := t.Tx.Commit()
+	t.c.ExecContext(context.Background(), "BEGIN DBMS_FLASHBACK.DISABLE; END;", nil)
+	return err
+}
+
+func (t *flashbackPinnedTx) Rollback() error {
+	err := t.Tx.Rollback()
+	t.c.ExecContext(context.Background(), "BEGIN DBMS_FLASHBACK.DISABLE; END;", nil)
+	return err
+}
+
+// TxSnapshotSCN returns the database's current system change number, as
+// read from V$DATABASE.CURRENT_SCN through tx. database/sql hands back a
+// plain *sql.Tx, which godror cannot attach methods to, so this is a
+// function rather than a Tx.SnapshotSCN method.
+//
+// V$DATABASE.CURRENT_SCN is the live SCN, not the one tx's snapshot was
+// taken at, so the value moves on as other transactions commit. Called
+// immediately after BeginTx, before any other statement on tx, it is the
+// closest reading of the SCN a LevelReadOnlySnapshot or
+// LevelSerializableSnapshot transaction sees, not an exact one. For a
+// transaction pinned with TxOptions.SnapshotSCN it returns the current
+// SCN, not the pinned one.
+//
+// Callers can record the returned SCN and later reopen a transaction at
+// that point via ContextWithTxOptions(ctx, godror.TxOptions{SnapshotSCN: scn}).
+func TxSnapshotSCN(ctx context.Context, tx *sql.Tx) (uint64, error) {
+	var scn uint64
+	row := tx.QueryRowContext(ctx, "SELECT CURRENT_SCN FROM V$DATABASE")
+	if err := row.Scan(&scn); err != nil {
+		return 0, fmt.Errorf("snapshotSCN: %w", err)
+	}
+	return scn, nil
+}
@@ -87,7 +87,8 @@ func TestStatWithLobs(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer ms.Close()
-	if _, err = ms.Fetch(ctx); err != nil {
+	first, err := ms.Fetch(ctx)
+	if err != nil {
 		var c interface{ Code() int }
 		if errors.As(err, &c) && c.Code() == 942 {
 			t.Skip(err)
@@ -95,6 +96,7 @@ func TestStatWithLobs(t *testing.T) {
 		}
 		t.Fatal(err)
 	}
+	t.Logf("snapshot SCN: %d", ms.scn)
 
 	for i := 0; i < 100; i++ {
 		if err := ctx.Err(); err != nil {
@@ -105,13 +107,30 @@ func TestStatWithLobs(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
+		// The transaction is a read-only snapshot (ms.scn is the SCN read
+		// right after BeginTx), so every fetch of the same row must return
+		// byte-identical sql_fulltext LOB contents, even though
+		// gv$sqlstats is constantly changing underneath it.
+		for j, e := range events {
+			if j >= len(first) {
+				break
+			}
+			if e.ID == first[j].ID && e.Text != first[j].Text {
+				t.Errorf("fetch %d, row %d: sql_fulltext changed under a pinned snapshot: %q != %q", i, j, e.Text, first[j].Text)
+			}
+		}
 	}
 }
 
 func newMetricSet(ctx context.Context, db *sql.DB) (*metricSet, error) {
 	qry := "select /* metricset: sqlstats */ inst_id, sql_fulltext, last_active_time from gv$sqlstats WHERE ROWNUM < 11"
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: godror.LevelReadOnlySnapshot})
+	if err != nil {
+		return nil, err
+	}
+	scn, err := godror.TxSnapshotSCN(ctx, tx)
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 	stmt, err := tx.PrepareContext(ctx, qry)
@@ -119,12 +138,13 @@ func newMetricSet(ctx context.Context, db *sql.DB) (*metricSet, error) {
 		return nil, err
 	}
 
-	return &metricSet{tx: tx, stmt: stmt}, nil
+	return &metricSet{tx: tx, stmt: stmt, scn: scn}, nil
 }
 
 type metricSet struct {
 	tx   *sql.Tx
 	stmt *sql.Stmt
+	scn  uint64
 }
 
 func (m *metricSet) Close() error {